@@ -0,0 +1,54 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// HeaderValue is a header name/value pair to add to a request or
+// response. Value may use Envoy's command operators, for example
+// "%DOWNSTREAM_REMOTE_ADDRESS%", which are passed through unchanged.
+type HeaderValue struct {
+	// Name of the header.
+	Name string `json:"name"`
+
+	// Value of the header. May reference an Envoy command operator.
+	Value string `json:"value"`
+
+	// Append, if true, appends Value to any existing header of the
+	// same Name instead of replacing it.
+	// +optional
+	Append bool `json:"append,omitempty"`
+}
+
+// HeadersPolicy describes header add/remove mutations that can be
+// applied on a Route, a weighted Service, or a virtualhost.
+type HeadersPolicy struct {
+	// RequestHeadersToAdd lists headers to add/append to the request
+	// before it is forwarded upstream.
+	// +optional
+	RequestHeadersToAdd []HeaderValue `json:"requestHeadersToAdd,omitempty"`
+
+	// RequestHeadersToRemove lists header names to strip from the
+	// request before it is forwarded upstream.
+	// +optional
+	RequestHeadersToRemove []string `json:"requestHeadersToRemove,omitempty"`
+
+	// ResponseHeadersToAdd lists headers to add/append to the response
+	// before it is returned downstream.
+	// +optional
+	ResponseHeadersToAdd []HeaderValue `json:"responseHeadersToAdd,omitempty"`
+
+	// ResponseHeadersToRemove lists header names to strip from the
+	// response before it is returned downstream.
+	// +optional
+	ResponseHeadersToRemove []string `json:"responseHeadersToRemove,omitempty"`
+}
@@ -0,0 +1,99 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// JWTProvider defines a JSON Web Token issuer that Envoy should trust to
+// authenticate requests for this virtualhost. It is referenced by name
+// from a Route's Requires field.
+type JWTProvider struct {
+	// Name of the provider. Must be unique within the virtualhost.
+	Name string `json:"name"`
+
+	// Issuer is the expected "iss" claim. If unset, the issuer is not
+	// checked.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// JWKSURI is the URI Envoy retrieves the provider's JSON Web Key
+	// Set from. Exactly one of JWKSURI or InlineJWKS must be set.
+	// +optional
+	JWKSURI string `json:"jwksURI,omitempty"`
+
+	// InlineJWKS is a literal JWKS document for providers that do not
+	// expose a discovery endpoint. Exactly one of JWKSURI or
+	// InlineJWKS must be set.
+	// +optional
+	InlineJWKS string `json:"inlineJWKS,omitempty"`
+
+	// Audiences restricts acceptable tokens to those whose "aud" claim
+	// contains one of these values.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// ForwardJWT, if true, forwards the validated JWT to the upstream
+	// in ForwardHeader.
+	// +optional
+	ForwardJWT bool `json:"forwardJWT,omitempty"`
+
+	// ForwardHeader is the header name the validated JWT is forwarded
+	// in. Defaults to "Authorization".
+	// +optional
+	ForwardHeader string `json:"forwardHeader,omitempty"`
+
+	// FromHeaders lists additional headers Envoy should extract the
+	// token from.
+	// +optional
+	FromHeaders []JWTHeader `json:"fromHeaders,omitempty"`
+
+	// FromParams lists query parameters Envoy should extract the token
+	// from.
+	// +optional
+	FromParams []string `json:"fromParams,omitempty"`
+}
+
+// JWTHeader identifies a header that may carry a JWT.
+type JWTHeader struct {
+	// Name of the header, e.g. "x-goog-iap-jwt-assertion".
+	Name string `json:"name"`
+
+	// ValuePrefix is stripped from the header's value before the
+	// remainder is treated as the token, e.g. "Bearer ".
+	// +optional
+	ValuePrefix string `json:"valuePrefix,omitempty"`
+}
+
+// JWTRequires describes the JWT validation a Route requires before its
+// request is forwarded upstream. Exactly one of Provider, RequiresAny,
+// or RequiresAll must be set.
+type JWTRequires struct {
+	// Provider names a single JWTProvider that must validate the
+	// request.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// RequiresAny lists provider names of which at least one must
+	// validate the request.
+	// +optional
+	RequiresAny []string `json:"requiresAny,omitempty"`
+
+	// RequiresAll lists provider names which must all validate the
+	// request.
+	// +optional
+	RequiresAll []string `json:"requiresAll,omitempty"`
+
+	// AllowMissing allows requests carrying no JWT through, while
+	// still rejecting an invalid one.
+	// +optional
+	AllowMissing bool `json:"allowMissing,omitempty"`
+}
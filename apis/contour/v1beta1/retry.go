@@ -0,0 +1,126 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidRetryOnConditions lists the retryOn conditions Contour accepts,
+// matching the grammar Envoy's router.retry_policy supports.
+var ValidRetryOnConditions = map[string]bool{
+	"5xx":                    true,
+	"gateway-error":          true,
+	"reset":                  true,
+	"connect-failure":        true,
+	"retriable-4xx":          true,
+	"refused-stream":         true,
+	"retriable-status-codes": true,
+}
+
+// ValidateRetryOn checks retryOn against the grammar recorded in
+// ValidRetryOnConditions, returning an error naming the first
+// unrecognised condition. An empty retryOn is valid.
+func ValidateRetryOn(retryOn string) error {
+	if retryOn == "" {
+		return nil
+	}
+	for _, cond := range strings.Split(retryOn, ",") {
+		cond = strings.TrimSpace(cond)
+		if !ValidRetryOnConditions[cond] {
+			return fmt.Errorf("invalid retryOn condition %q", cond)
+		}
+	}
+	return nil
+}
+
+// RetryPolicy allows the user to configure the retry behaviour for a
+// Route.
+type RetryPolicy struct {
+	// NumRetries is the maximum number of retries. Defaults to 1 if
+	// not supplied.
+	// +optional
+	NumRetries int64 `json:"numRetries,omitempty"`
+
+	// PerTryTimeout specifies the timeout per retry attempt, e.g.
+	// "150ms". Ignored if not supplied.
+	// +optional
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+
+	// RetryOn is a comma separated list of retry conditions, e.g.
+	// "5xx,gateway-error,reset,connect-failure,retriable-4xx,
+	// refused-stream". Each entry must be a member of
+	// ValidRetryOnConditions.
+	// +optional
+	RetryOn string `json:"retryOn,omitempty"`
+
+	// RetriableStatusCodes lists additional HTTP status codes that
+	// should be retried. Only takes effect if RetryOn includes
+	// "retriable-status-codes".
+	// +optional
+	RetriableStatusCodes []uint32 `json:"retriableStatusCodes,omitempty"`
+
+	// RetryBackOff configures the backoff applied between retries.
+	// +optional
+	RetryBackOff *RetryBackOff `json:"retryBackOff,omitempty"`
+
+	// RetriableRequestHeaders, if non-empty, restricts retries to
+	// requests whose headers match one of these conditions.
+	// +optional
+	RetriableRequestHeaders []HeaderMatchCondition `json:"retriableRequestHeaders,omitempty"`
+
+	// RetriableHeaders, if non-empty, triggers a retry whenever the
+	// upstream response's headers match one of these conditions,
+	// regardless of status code.
+	// +optional
+	RetriableHeaders []HeaderMatchCondition `json:"retriableHeaders,omitempty"`
+}
+
+// Validate checks that RetryOn holds only conditions from
+// ValidRetryOnConditions.
+func (r *RetryPolicy) Validate() error {
+	return ValidateRetryOn(r.RetryOn)
+}
+
+// HeaderMatchCondition describes a single header match used to
+// restrict when a RetryPolicy's RetriableRequestHeaders or
+// RetriableHeaders applies.
+type HeaderMatchCondition struct {
+	// Name of the header to match.
+	Name string `json:"name"`
+
+	// Value the header must equal. Ignored if Present is true.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Present, if true, matches the header's presence rather than its
+	// Value.
+	// +optional
+	Present bool `json:"present,omitempty"`
+}
+
+// RetryBackOff configures the base and maximum intervals of the
+// exponential backoff Envoy applies between retry attempts.
+type RetryBackOff struct {
+	// BaseInterval is the initial backoff interval, e.g. "25ms".
+	// Defaults to Envoy's own default of 25ms if unset.
+	// +optional
+	BaseInterval string `json:"baseInterval,omitempty"`
+
+	// MaxInterval caps the backoff interval. Defaults to 10x
+	// BaseInterval if unset.
+	// +optional
+	MaxInterval string `json:"maxInterval,omitempty"`
+}
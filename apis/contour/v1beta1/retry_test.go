@@ -0,0 +1,49 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import "testing"
+
+func TestValidateRetryOn(t *testing.T) {
+	tests := map[string]struct {
+		retryOn string
+		wantErr bool
+	}{
+		"empty":              {retryOn: "", wantErr: false},
+		"single valid":       {retryOn: "5xx", wantErr: false},
+		"multiple valid":     {retryOn: "5xx,connect-failure,retriable-status-codes", wantErr: false},
+		"spaces tolerated":   {retryOn: "5xx, connect-failure", wantErr: false},
+		"unknown condition":  {retryOn: "bogus", wantErr: true},
+		"one bad among good": {retryOn: "5xx,bogus", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRetryOn(tc.retryOn)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	rp := &RetryPolicy{RetryOn: "not-a-real-condition"}
+	if err := rp.Validate(); err == nil {
+		t.Fatal("expected an error for an invalid retryOn condition")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// TimeoutPolicy allows a Route to specify how long Envoy should wait
+// for a response, and how long it will tolerate an idle stream. Values
+// are parsed with time.ParseDuration; the literal string "infinity"
+// disables the respective timeout.
+type TimeoutPolicy struct {
+	// Request is the maximum duration of the entire downstream
+	// request/response. Defaults to Envoy's 15 second default if
+	// unset.
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// Idle is the maximum time of inactivity between bytes on the
+	// stream before it is closed. Unlike Request, it does not bound
+	// the lifetime of a long-lived stream, only the gaps within it.
+	// +optional
+	Idle string `json:"idle,omitempty"`
+}
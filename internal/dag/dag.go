@@ -0,0 +1,214 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dag provides a directed acyclic graph of the relationship
+// between Kubernetes Ingress, IngressRoute, Service, and Secret objects.
+package dag
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Vertex is a node in the DAG that can be visited.
+type Vertex interface {
+	Visit(func(Vertex))
+}
+
+// TCPService represents a Kubernetes Service and port that can be
+// addressed by Envoy as an upstream.
+type TCPService struct {
+	Name, Namespace string
+
+	*v1.ServicePort
+}
+
+// Route represents a single HTTP route entry, matched on Prefix, and
+// forwarded to one or more Clusters.
+type Route struct {
+	Prefix string
+
+	// Websocket enables support for upgrading the connection to
+	// WebSocket for this route.
+	Websocket bool
+
+	// HTTPSUpgrade, if true, redirects this route to HTTPS.
+	HTTPSUpgrade bool
+
+	RetryPolicy *RetryPolicy
+
+	TimeoutPolicy *TimeoutPolicy
+
+	// JWTRequires, if set, describes the JWT validation requirement
+	// that must be satisfied for a request to be forwarded on this
+	// route.
+	JWTRequires *JWTRequires
+
+	// Headers holds request/response header mutations applied to
+	// every Cluster reached via this Route.
+	Headers Headers
+
+	Clusters []*Cluster
+
+	// Delegates holds weighted splits that forward to another
+	// IngressRoute's own splitter rather than directly to a Cluster.
+	// Use FlattenClusters to resolve a Route's full, flattened set of
+	// Clusters including these delegations.
+	Delegates []*Delegate
+}
+
+// Cluster holds the information necessary to proxy to an upstream
+// TCPService, including an optional weight used when more than one
+// Cluster is attached to a Route.
+type Cluster struct {
+	Upstream *TCPService
+
+	// Weight is the relative weight of this Cluster when more than one
+	// Cluster is present on a Route. A Weight of zero means this
+	// Cluster receives no traffic, unless every Cluster on the Route
+	// has a Weight of zero, in which case they share traffic evenly.
+	Weight uint32
+
+	// ConnectTimeout is the time Envoy will wait for an upstream TCP
+	// connection to this Cluster to be established before giving up.
+	// A value of zero implies the Envoy default of 5 seconds applies.
+	ConnectTimeout time.Duration
+
+	// Headers holds request/response header mutations applied only
+	// when traffic is split to this Cluster, layered on top of the
+	// owning Route's Headers.
+	Headers Headers
+
+	// TimeoutPolicy is this Cluster's own request/idle timeout policy,
+	// carried through FlattenClusters from the inner splitter's leaf
+	// definition it was delegated from. The envoy package's
+	// translators do not read it: the timeouts sent to Envoy come from
+	// the owning Route's TimeoutPolicy only.
+	TimeoutPolicy *TimeoutPolicy
+}
+
+// RetryPolicy describes the retry policy, if any, for a Route.
+type RetryPolicy struct {
+	// RetryOn is a string of comma separated retry conditions, for
+	// example "5xx,connect-failure". Valid conditions are "5xx",
+	// "gateway-error", "reset", "connect-failure", "retriable-4xx",
+	// "refused-stream", and "retriable-status-codes". The last of
+	// these only takes effect when RetriableStatusCodes is non-empty.
+	RetryOn string
+
+	// NumRetries is the maximum number of retries. Defaults to 1 if
+	// not supplied.
+	NumRetries uint32
+
+	// PerTryTimeout specifies the timeout per retry attempt. Ignored
+	// if not supplied.
+	PerTryTimeout time.Duration
+
+	// RetriableStatusCodes lists additional HTTP status codes that
+	// should be retried. Only takes effect if RetryOn includes
+	// "retriable-status-codes".
+	RetriableStatusCodes []uint32
+
+	// RetryBackOff configures the exponential backoff applied between
+	// retry attempts. If nil, Envoy's default backoff applies.
+	RetryBackOff *RetryBackOff
+
+	// RetriableRequestHeaders, if non-empty, restricts retries to
+	// requests whose headers match one of these HeaderMatchConditions.
+	RetriableRequestHeaders []HeaderMatchCondition
+
+	// RetriableHeaders, if non-empty, triggers a retry whenever the
+	// upstream response's headers match one of these
+	// HeaderMatchConditions, regardless of status code.
+	RetriableHeaders []HeaderMatchCondition
+}
+
+// RetryBackOff configures the base and maximum intervals of the
+// exponential backoff Envoy applies between retry attempts.
+type RetryBackOff struct {
+	// BaseInterval is the initial backoff interval. Envoy's default of
+	// 25ms applies if zero.
+	BaseInterval time.Duration
+
+	// MaxInterval caps the backoff interval, growing exponentially
+	// from BaseInterval on each retry. Envoy defaults this to 10x
+	// BaseInterval if zero.
+	MaxInterval time.Duration
+}
+
+// HeaderMatchCondition describes a single header match used by a
+// RetryPolicy's RetriableRequestHeaders (matched against the request)
+// or RetriableHeaders (matched against the upstream response).
+type HeaderMatchCondition struct {
+	Name  string
+	Value string
+
+	// Present, if true, matches the header's presence rather than its
+	// Value.
+	Present bool
+}
+
+// TimeoutPolicy describes the timeout policy, if any, for a Route.
+type TimeoutPolicy struct {
+	// Timeout specifies the maximum time allowed for the entire
+	// downstream request, from the start of the request to the end of
+	// the response (Envoy's RouteAction.Timeout). A value of zero
+	// implies the Envoy default of 15 seconds applies. A value of -1
+	// means infinity, which is required for long-lived streams such as
+	// gRPC streaming or long-poll endpoints.
+	Timeout time.Duration
+
+	// IdleTimeout specifies the maximum time of inactivity between
+	// bytes sent or received on the downstream or upstream stream
+	// before it is closed. Unlike Timeout, it does not bound the
+	// overall lifetime of a long-lived stream, only the gaps within
+	// it. A value of zero implies the Envoy default applies. A value
+	// of -1 disables the idle timeout.
+	IdleTimeout time.Duration
+}
+
+// VirtualHost represents a named L7 virtual host.
+type VirtualHost struct {
+	// Name is the fully qualified domain name of a network host,
+	// as defined by RFC 3986.
+	Name string
+
+	// Headers holds request/response header mutations applied to
+	// every Route on this VirtualHost.
+	Headers Headers
+
+	// JWTProviders lists the JWT issuers a listener builder should
+	// configure the jwt_authn HTTP filter to trust on behalf of this
+	// VirtualHost's Routes. A Route's JWTRequires refers to one of
+	// these providers by name.
+	JWTProviders []*JWTProvider
+
+	Routes []*Route
+}
+
+func (v *VirtualHost) Visit(f func(Vertex)) {
+	for _, r := range v.Routes {
+		f(r)
+	}
+}
+
+func (r *Route) Visit(f func(Vertex)) {
+	for _, c := range r.Clusters {
+		f(c)
+	}
+}
+
+func (c *Cluster) Visit(func(Vertex)) {
+	// Cluster is a leaf in the DAG.
+}
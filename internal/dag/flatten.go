@@ -0,0 +1,194 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+// Delegate represents a single weighted split of a Route that forwards
+// to a nested IngressRoute splitter rather than directly to a Cluster.
+// Weight is relative to the total of all Clusters and Delegates
+// on the parent Route.
+type Delegate struct {
+	// Weight is this Delegate's share of the parent Route's traffic.
+	Weight uint32
+
+	// Clusters are the leaves of the nested splitter being delegated
+	// to.
+	Clusters []*Cluster
+
+	// Headers are the nested splitter's own virtualhost/route level
+	// header policy, layered underneath the parent Route's Headers.
+	Headers Headers
+
+	// TimeoutPolicy is the nested splitter's own route-level timeout
+	// policy. Its explicit fields win over a leaf's own TimeoutPolicy
+	// when both are merged in FlattenClusters.
+	TimeoutPolicy *TimeoutPolicy
+}
+
+// FlattenClusters computes the single-level list of Clusters that
+// results from resolving each of route's Delegates, multiplying the
+// outer weight of a delegation by the inner weight of each leaf it
+// points to, then normalizing the combined list back to whole-number
+// weights. A Route with no Delegates returns its own Clusters
+// unmodified.
+//
+// For an outer split of weight Wo (out of outer total To) pointing at
+// an inner splitter whose leaves sum to Ti, each inner leaf of weight
+// Wi is emitted with effective weight floor(Wo * Wi * scale / (To * Ti)),
+// where scale is the fixed factor 10000. A Cluster attached directly
+// to the Route gets floor(W * scale / To).
+func FlattenClusters(route *Route) []*Cluster {
+	if len(route.Delegates) == 0 {
+		return route.Clusters
+	}
+
+	outerTotal := routeWeightTotal(route)
+
+	// scale keeps fractional effective weights from collapsing to
+	// zero when an outer split's share of an inner splitter's total is
+	// small; LCM-style scaling isn't worth the complexity here, so a
+	// fixed factor large enough for realistic delegation depths is
+	// used instead.
+	const scale = 10000
+
+	var flattened []*Cluster
+	for _, d := range route.Delegates {
+		innerTotal := clusterWeightTotal(d.Clusters)
+		for _, leaf := range d.Clusters {
+			effective := delegateWeight(d.Weight, outerTotal, leaf.Weight, innerTotal, scale)
+			flattened = append(flattened, &Cluster{
+				Upstream:       leaf.Upstream,
+				Weight:         effective,
+				ConnectTimeout: leaf.ConnectTimeout,
+				Headers:        mergeHeaders(d.Headers, leaf.Headers),
+				TimeoutPolicy:  mergeTimeoutPolicy(d.TimeoutPolicy, leaf.TimeoutPolicy),
+			})
+		}
+	}
+	// Clusters attached directly to this Route (not via a Delegate)
+	// take the same share of outerTotal that a Delegate with the same
+	// Weight would, so scale them onto the same basis as the
+	// delegated entries above before the two are compared.
+	total := outerTotal
+	if total == 0 {
+		total = 1
+	}
+	for _, c := range route.Clusters {
+		flattened = append(flattened, &Cluster{
+			Upstream:       c.Upstream,
+			Weight:         uint32(uint64(c.Weight) * uint64(scale) / uint64(total)),
+			ConnectTimeout: c.ConnectTimeout,
+			Headers:        c.Headers,
+			TimeoutPolicy:  c.TimeoutPolicy,
+		})
+	}
+	return normalizeWeights(flattened)
+}
+
+func routeWeightTotal(route *Route) uint32 {
+	var total uint32
+	for _, c := range route.Clusters {
+		total += c.Weight
+	}
+	for _, d := range route.Delegates {
+		total += d.Weight
+	}
+	return total
+}
+
+func clusterWeightTotal(clusters []*Cluster) uint32 {
+	var total uint32
+	for _, c := range clusters {
+		total += c.Weight
+	}
+	return total
+}
+
+// delegateWeight computes Wo*Wi*scale/(To*Ti), truncated to an
+// integer. If the inner splitter has no traffic at all (innerTotal is
+// zero, i.e. every leaf has Weight 0), the leaf's own Weight, which is
+// then zero, is returned unscaled, so such a Delegate's leaves get no
+// weight in the flattened list.
+func delegateWeight(outerWeight, outerTotal, innerWeight, innerTotal, scale uint32) uint32 {
+	if innerTotal == 0 {
+		return innerWeight
+	}
+	if outerTotal == 0 {
+		outerTotal = 1
+	}
+	num := uint64(outerWeight) * uint64(innerWeight) * uint64(scale)
+	return uint32(num / (uint64(outerTotal) * uint64(innerTotal)))
+}
+
+// normalizeWeights reduces an arbitrarily scaled set of Cluster weights
+// back down to the smallest set of whole numbers that preserves their
+// relative proportions, by dividing through by their GCD.
+func normalizeWeights(clusters []*Cluster) []*Cluster {
+	g := uint32(0)
+	for _, c := range clusters {
+		g = gcd(g, c.Weight)
+	}
+	if g > 1 {
+		for _, c := range clusters {
+			c.Weight /= g
+		}
+	}
+	return clusters
+}
+
+func gcd(a, b uint32) uint32 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// mergeHeaders layers child's header policy underneath parent's,
+// letting the parent's explicit fields win while falling back to the
+// child's for anything the parent leaves unset.
+func mergeHeaders(parent, child Headers) Headers {
+	merged := child
+	if len(parent.RequestHeadersToAdd) > 0 {
+		merged.RequestHeadersToAdd = parent.RequestHeadersToAdd
+	}
+	if len(parent.RequestHeadersToRemove) > 0 {
+		merged.RequestHeadersToRemove = parent.RequestHeadersToRemove
+	}
+	if len(parent.ResponseHeadersToAdd) > 0 {
+		merged.ResponseHeadersToAdd = parent.ResponseHeadersToAdd
+	}
+	if len(parent.ResponseHeadersToRemove) > 0 {
+		merged.ResponseHeadersToRemove = parent.ResponseHeadersToRemove
+	}
+	return merged
+}
+
+// mergeTimeoutPolicy layers child's timeout policy underneath parent's,
+// letting the parent's explicit (non-zero) fields win while falling
+// back to the child's for anything the parent leaves unset.
+func mergeTimeoutPolicy(parent, child *TimeoutPolicy) *TimeoutPolicy {
+	if parent == nil {
+		return child
+	}
+	if child == nil {
+		return parent
+	}
+	merged := *child
+	if parent.Timeout != 0 {
+		merged.Timeout = parent.Timeout
+	}
+	if parent.IdleTimeout != 0 {
+		merged.IdleTimeout = parent.IdleTimeout
+	}
+	return &merged
+}
@@ -0,0 +1,237 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+)
+
+func upstream(name string) *TCPService {
+	return &TCPService{
+		Name:      name,
+		Namespace: "default",
+		ServicePort: &v1.ServicePort{
+			Port: 8080,
+		},
+	}
+}
+
+func weights(clusters []*Cluster) map[string]uint32 {
+	out := make(map[string]uint32, len(clusters))
+	for _, c := range clusters {
+		out[c.Upstream.Name] += c.Weight
+	}
+	return out
+}
+
+func TestFlattenClustersNoDelegates(t *testing.T) {
+	route := &Route{
+		Clusters: []*Cluster{{
+			Upstream: upstream("direct"),
+			Weight:   1,
+		}},
+	}
+	got := FlattenClusters(route)
+	if diff := cmp.Diff(route.Clusters, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestFlattenClustersTwoLevel(t *testing.T) {
+	// outer: a (weight 50), b (weight 50)
+	// a delegates to a splitter with x(weight 1), y(weight 1)
+	route := &Route{
+		Delegates: []*Delegate{{
+			Weight: 50,
+			Clusters: []*Cluster{
+				{Upstream: upstream("x"), Weight: 1},
+				{Upstream: upstream("y"), Weight: 1},
+			},
+		}},
+		Clusters: []*Cluster{
+			{Upstream: upstream("b"), Weight: 50},
+		},
+	}
+
+	got := weights(FlattenClusters(route))
+	if got["x"] != got["y"] {
+		t.Fatalf("expected x and y to split their delegate's share evenly, got %v", got)
+	}
+	if got["b"] != 2*got["x"] {
+		t.Fatalf("expected b (50%% direct) to get twice x's share (25%% each), got %v", got)
+	}
+}
+
+func TestFlattenClustersThreeLevel(t *testing.T) {
+	// a delegates to b's splitter, which itself delegates to c's splitter.
+	leaf := &Route{
+		Delegates: []*Delegate{{
+			Weight:   100,
+			Clusters: []*Cluster{{Upstream: upstream("c"), Weight: 1}},
+		}},
+	}
+	cClusters := FlattenClusters(leaf)
+
+	mid := &Route{
+		Delegates: []*Delegate{{
+			Weight:   100,
+			Clusters: cClusters,
+		}},
+	}
+
+	got := weights(FlattenClusters(mid))
+	if got["c"] == 0 {
+		t.Fatalf("expected c to retain non-zero weight through two levels of delegation, got %v", got)
+	}
+}
+
+func TestFlattenClustersUnevenTotals(t *testing.T) {
+	// inner totals of 33/33/34 should come out roughly even after
+	// normalization.
+	route := &Route{
+		Delegates: []*Delegate{{
+			Weight: 100,
+			Clusters: []*Cluster{
+				{Upstream: upstream("x"), Weight: 33},
+				{Upstream: upstream("y"), Weight: 33},
+				{Upstream: upstream("z"), Weight: 34},
+			},
+		}},
+	}
+
+	got := weights(FlattenClusters(route))
+	if got["x"] == 0 || got["y"] == 0 || got["z"] == 0 {
+		t.Fatalf("expected every leaf to retain a non-zero share, got %v", got)
+	}
+	if got["z"] < got["x"] || got["z"] < got["y"] {
+		t.Fatalf("expected z (largest inner weight) to receive the largest share, got %v", got)
+	}
+}
+
+func TestFlattenClustersDirectClusterLargeWeightNoOverflow(t *testing.T) {
+	// A direct Cluster weight above the uint32/scale overflow
+	// threshold (~429,496 at scale=10000) must not wrap around to a
+	// small or zero effective weight.
+	route := &Route{
+		Delegates: []*Delegate{{
+			Weight:   1,
+			Clusters: []*Cluster{{Upstream: upstream("delegated"), Weight: 1}},
+		}},
+		Clusters: []*Cluster{
+			{Upstream: upstream("direct"), Weight: 1000000},
+		},
+	}
+
+	got := weights(FlattenClusters(route))
+	if got["direct"] <= got["delegated"] {
+		t.Fatalf("expected direct cluster's large weight to dominate, got %v", got)
+	}
+}
+
+func TestFlattenClustersPreservesAndMergesTimeoutPolicy(t *testing.T) {
+	route := &Route{
+		Delegates: []*Delegate{{
+			Weight: 100,
+			TimeoutPolicy: &TimeoutPolicy{
+				Timeout: 30 * time.Second,
+			},
+			Clusters: []*Cluster{
+				{
+					Upstream: upstream("x"),
+					Weight:   1,
+					TimeoutPolicy: &TimeoutPolicy{
+						Timeout:     90 * time.Second,
+						IdleTimeout: 5 * time.Minute,
+					},
+				},
+			},
+		}},
+	}
+
+	got := FlattenClusters(route)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 cluster, got %d", len(got))
+	}
+	tp := got[0].TimeoutPolicy
+	if tp == nil {
+		t.Fatal("expected the leaf's TimeoutPolicy to be preserved, got nil")
+	}
+	if tp.Timeout != 30*time.Second {
+		t.Fatalf("expected the delegate's explicit Timeout to win, got %v", tp.Timeout)
+	}
+	if tp.IdleTimeout != 5*time.Minute {
+		t.Fatalf("expected the leaf's IdleTimeout to fall through since the delegate left it unset, got %v", tp.IdleTimeout)
+	}
+}
+
+func TestMergeTimeoutPolicy(t *testing.T) {
+	tests := map[string]struct {
+		parent, child *TimeoutPolicy
+		want          *TimeoutPolicy
+	}{
+		"both nil": {
+			parent: nil,
+			child:  nil,
+			want:   nil,
+		},
+		"only child set": {
+			parent: nil,
+			child:  &TimeoutPolicy{Timeout: 10 * time.Second},
+			want:   &TimeoutPolicy{Timeout: 10 * time.Second},
+		},
+		"only parent set": {
+			parent: &TimeoutPolicy{Timeout: 10 * time.Second},
+			child:  nil,
+			want:   &TimeoutPolicy{Timeout: 10 * time.Second},
+		},
+		"parent's explicit field wins": {
+			parent: &TimeoutPolicy{Timeout: 10 * time.Second},
+			child:  &TimeoutPolicy{Timeout: 20 * time.Second, IdleTimeout: 60 * time.Second},
+			want:   &TimeoutPolicy{Timeout: 10 * time.Second, IdleTimeout: 60 * time.Second},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := mergeTimeoutPolicy(tc.parent, tc.child)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestFlattenClustersDegenerateZeroWeightLeaf(t *testing.T) {
+	route := &Route{
+		Delegates: []*Delegate{{
+			Weight: 100,
+			Clusters: []*Cluster{
+				{Upstream: upstream("x"), Weight: 1},
+				{Upstream: upstream("dark"), Weight: 0},
+			},
+		}},
+	}
+
+	got := weights(FlattenClusters(route))
+	if got["dark"] != 0 {
+		t.Fatalf("expected dark launch leaf to remain at weight 0, got %d", got["dark"])
+	}
+	if got["x"] == 0 {
+		t.Fatal("expected the only traffic-carrying leaf to retain non-zero weight")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+// HeaderValue is a header name/value pair to be added to a request or
+// response. Value may reference Envoy's command operators (for
+// example "%DOWNSTREAM_REMOTE_ADDRESS%"), which are passed through to
+// Envoy unchanged.
+type HeaderValue struct {
+	Name  string
+	Value string
+
+	// Append, if true, appends Value to any existing header of the
+	// same Name instead of replacing it. Envoy is always told this
+	// value explicitly, so leaving it unset is equivalent to setting
+	// it to false (replace), not Envoy's own default of true.
+	Append bool
+}
+
+// Headers holds the set of header mutations that can be applied at a
+// Route, Cluster, or VirtualHost.
+type Headers struct {
+	RequestHeadersToAdd     []HeaderValue
+	RequestHeadersToRemove  []string
+	ResponseHeadersToAdd    []HeaderValue
+	ResponseHeadersToRemove []string
+}
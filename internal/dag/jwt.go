@@ -0,0 +1,88 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+// JWTProvider describes a single JWT issuer that Envoy should trust
+// to validate JSON Web Tokens on behalf of one or more VirtualHosts.
+type JWTProvider struct {
+	// Name identifies this provider within an IngressRoute's
+	// virtualhost and is referenced by Route.JWTRequires.
+	Name string
+
+	// Issuer is the expected "iss" claim on the token. If empty, the
+	// issuer is not checked.
+	Issuer string
+
+	// JWKSURI is the URI Envoy fetches the provider's JSON Web Key Set
+	// from. Mutually exclusive with InlineJWKS.
+	JWKSURI string
+
+	// InlineJWKS is a literal JWKS document, used in place of JWKSURI
+	// for providers that do not publish a discovery endpoint.
+	InlineJWKS string
+
+	// Audiences restricts acceptable tokens to those whose "aud" claim
+	// contains one of these values. If empty, the audience is not
+	// checked.
+	Audiences []string
+
+	// ForwardJWT, if true, forwards the verified JWT to the upstream
+	// in the ForwardHeader.
+	ForwardJWT bool
+
+	// ForwardHeader is the header name the verified JWT is forwarded
+	// in when ForwardJWT is true. Defaults to "Authorization".
+	ForwardHeader string
+
+	// FromHeaders lists additional header names (and optional Bearer
+	// style prefixes) that Envoy should extract the token from, in
+	// addition to the default Authorization header.
+	FromHeaders []JWTHeader
+
+	// FromParams lists query parameter names that Envoy should extract
+	// the token from.
+	FromParams []string
+}
+
+// JWTHeader identifies a header Envoy should extract a JWT from.
+type JWTHeader struct {
+	// Name is the header name, e.g. "x-goog-iap-jwt-assertion".
+	Name string
+
+	// ValuePrefix is stripped from the header value before the
+	// remainder is treated as the token, e.g. "Bearer ".
+	ValuePrefix string
+}
+
+// JWTRequires describes the JWT validation requirement that must be
+// satisfied before a Route's request is forwarded upstream.
+type JWTRequires struct {
+	// ProviderName is required unless RequiresAny or RequiresAll is
+	// set, and names a single JWTProvider that must successfully
+	// validate the request.
+	ProviderName string
+
+	// RequiresAny lists provider names of which at least one must
+	// validate the request. Mutually exclusive with ProviderName and
+	// RequiresAll.
+	RequiresAny []string
+
+	// RequiresAll lists provider names which must all validate the
+	// request. Mutually exclusive with ProviderName and RequiresAny.
+	RequiresAll []string
+
+	// AllowMissing, if true, allows the request through when it
+	// carries no JWT at all, while still rejecting an invalid one.
+	AllowMissing bool
+}
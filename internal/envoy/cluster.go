@@ -0,0 +1,37 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/heptio/contour/internal/dag"
+)
+
+// ClusterConnectTimeoutDefault is the connect timeout Envoy applies
+// when a dag.Cluster does not specify its own.
+const ClusterConnectTimeoutDefault = 5 * time.Second
+
+// Cluster creates a v2.Cluster for the supplied dag.Cluster.
+func Cluster(c *dag.Cluster) *v2.Cluster {
+	connectTimeout := c.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = ClusterConnectTimeoutDefault
+	}
+	return &v2.Cluster{
+		Name:           Clustername(c),
+		ConnectTimeout: connectTimeout,
+	}
+}
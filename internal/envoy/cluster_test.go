@@ -0,0 +1,58 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heptio/contour/internal/dag"
+	"k8s.io/api/core/v1"
+)
+
+func TestClusterConnectTimeout(t *testing.T) {
+	c := &dag.Cluster{
+		Upstream: &dag.TCPService{
+			Name:      "kuard",
+			Namespace: "default",
+			ServicePort: &v1.ServicePort{
+				Port: 8080,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		connectTimeout time.Duration
+		want           time.Duration
+	}{
+		"unset falls back to default": {
+			connectTimeout: 0,
+			want:           ClusterConnectTimeoutDefault,
+		},
+		"explicit value is respected": {
+			connectTimeout: 2500 * time.Millisecond,
+			want:           2500 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c.ConnectTimeout = tc.connectTimeout
+			got := Cluster(c).ConnectTimeout
+			if got != tc.want {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/heptio/contour/internal/dag"
+)
+
+// headersToAdd converts a []dag.HeaderValue into the
+// []*core.HeaderValueOption Envoy expects, passing Envoy command
+// operators such as %DOWNSTREAM_REMOTE_ADDRESS% straight through in
+// the value.
+func headersToAdd(headers []dag.HeaderValue) []*envoy_api_v2_core.HeaderValueOption {
+	if len(headers) == 0 {
+		return nil
+	}
+	hs := make([]*envoy_api_v2_core.HeaderValueOption, 0, len(headers))
+	for _, h := range headers {
+		hs = append(hs, &envoy_api_v2_core.HeaderValueOption{
+			Header: &envoy_api_v2_core.HeaderValue{
+				Key:   h.Name,
+				Value: h.Value,
+			},
+			Append: bool_(h.Append),
+		})
+	}
+	return hs
+}
@@ -0,0 +1,190 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	jwt_authn "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/jwt_authn/v2alpha"
+	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
+	"github.com/gogo/protobuf/types"
+	"github.com/heptio/contour/internal/dag"
+)
+
+// JWTAuthnFilterName is the name Envoy registers the JWT authentication
+// HTTP filter under.
+const JWTAuthnFilterName = "envoy.filters.http.jwt_authn"
+
+// JWTAuthnFilter builds the envoy.filters.http.jwt_authn HTTP filter for
+// the supplied set of providers. It returns nil if providers is empty,
+// so callers can unconditionally append the result to a filter chain.
+//
+// The filter must be chained ahead of any authorization filter (e.g.
+// RBAC) so that the verified JWT claims and per-route requirements are
+// available by the time authorization runs.
+func JWTAuthnFilter(providers []*dag.JWTProvider) *http.HttpFilter {
+	if len(providers) == 0 {
+		return nil
+	}
+	config := jwt_authn.JwtAuthentication{
+		Providers: make(map[string]*jwt_authn.JwtProvider, len(providers)),
+	}
+	for _, p := range providers {
+		config.Providers[p.Name] = jwtProvider(p)
+	}
+	return &http.HttpFilter{
+		Name: JWTAuthnFilterName,
+		ConfigType: &http.HttpFilter_TypedConfig{
+			TypedConfig: toAny(&config),
+		},
+	}
+}
+
+// JWTProvidersForVirtualHosts collects the distinct JWTProviders
+// configured across vhosts, deduplicating by Name, so a listener
+// builder can pass the result straight to JWTAuthnFilter when
+// assembling the HTTP connection manager's filter chain.
+func JWTProvidersForVirtualHosts(vhosts []*dag.VirtualHost) []*dag.JWTProvider {
+	var providers []*dag.JWTProvider
+	seen := make(map[string]bool)
+	for _, vh := range vhosts {
+		for _, p := range vh.JWTProviders {
+			if seen[p.Name] {
+				continue
+			}
+			seen[p.Name] = true
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+func jwtProvider(p *dag.JWTProvider) *jwt_authn.JwtProvider {
+	jp := &jwt_authn.JwtProvider{
+		Issuer:    p.Issuer,
+		Audiences: p.Audiences,
+		Forward:   p.ForwardJWT,
+	}
+	if p.ForwardJWT {
+		header := p.ForwardHeader
+		if header == "" {
+			header = "Authorization"
+		}
+		jp.ForwardPayloadHeader = header
+	}
+	if p.InlineJWKS != "" {
+		jp.JwksSourceSpecifier = &jwt_authn.JwtProvider_LocalJwks{
+			LocalJwks: &envoy_api_v2_core.DataSource{
+				Specifier: &envoy_api_v2_core.DataSource_InlineString{
+					InlineString: p.InlineJWKS,
+				},
+			},
+		}
+	} else {
+		jp.JwksSourceSpecifier = &jwt_authn.JwtProvider_RemoteJwks{
+			RemoteJwks: &jwt_authn.RemoteJwks{
+				HttpUri: &envoy_api_v2_core.HttpUri{
+					Uri: p.JWKSURI,
+					HttpUpstreamType: &envoy_api_v2_core.HttpUri_Cluster{
+						Cluster: p.JWKSURI,
+					},
+				},
+			},
+		}
+	}
+	for _, h := range p.FromHeaders {
+		jp.FromHeaders = append(jp.FromHeaders, &jwt_authn.JwtHeader{
+			Name:        h.Name,
+			ValuePrefix: h.ValuePrefix,
+		})
+	}
+	jp.FromParams = p.FromParams
+	return jp
+}
+
+// JWTPerRoute builds the per-route jwt_authn PerFilterConfig for the
+// supplied requirement, matching it to the JWTAuthnFilter emitted on
+// the listener. It returns nil if r is nil, so the caller can
+// unconditionally attach the result to a Route's TypedPerFilterConfig.
+func JWTPerRoute(r *dag.JWTRequires) *jwt_authn.PerRouteConfig {
+	if r == nil {
+		return nil
+	}
+	return &jwt_authn.PerRouteConfig{
+		RequirementSpecifier: &jwt_authn.PerRouteConfig_Requires{
+			Requires: jwtRequirement(r),
+		},
+	}
+}
+
+// jwtRequirement translates a dag.JWTRequires into the Envoy
+// JwtRequirement it corresponds to.
+func jwtRequirement(r *dag.JWTRequires) *jwt_authn.JwtRequirement {
+	req := jwtRequirementFor(r)
+	if !r.AllowMissing {
+		return req
+	}
+	return &jwt_authn.JwtRequirement{
+		RequiresType: &jwt_authn.JwtRequirement_RequiresAny{
+			RequiresAny: &jwt_authn.JwtRequirementOrList{
+				Requirements: []*jwt_authn.JwtRequirement{
+					req,
+					{
+						RequiresType: &jwt_authn.JwtRequirement_AllowMissing{
+							AllowMissing: &types.Empty{},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func jwtRequirementFor(r *dag.JWTRequires) *jwt_authn.JwtRequirement {
+	switch {
+	case len(r.RequiresAny) > 0:
+		return &jwt_authn.JwtRequirement{
+			RequiresType: &jwt_authn.JwtRequirement_RequiresAny{
+				RequiresAny: &jwt_authn.JwtRequirementOrList{
+					Requirements: providerNameRequirements(r.RequiresAny),
+				},
+			},
+		}
+	case len(r.RequiresAll) > 0:
+		return &jwt_authn.JwtRequirement{
+			RequiresType: &jwt_authn.JwtRequirement_RequiresAll{
+				RequiresAll: &jwt_authn.JwtRequirementAndList{
+					Requirements: providerNameRequirements(r.RequiresAll),
+				},
+			},
+		}
+	default:
+		return &jwt_authn.JwtRequirement{
+			RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+				ProviderName: r.ProviderName,
+			},
+		}
+	}
+}
+
+func providerNameRequirements(names []string) []*jwt_authn.JwtRequirement {
+	reqs := make([]*jwt_authn.JwtRequirement, 0, len(names))
+	for _, n := range names {
+		reqs = append(reqs, &jwt_authn.JwtRequirement{
+			RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+				ProviderName: n,
+			},
+		})
+	}
+	return reqs
+}
@@ -0,0 +1,194 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+
+	jwt_authn "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/jwt_authn/v2alpha"
+	"github.com/gogo/protobuf/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/heptio/contour/internal/dag"
+)
+
+func TestJWTAuthnFilter(t *testing.T) {
+	tests := map[string]struct {
+		providers []*dag.JWTProvider
+		want      bool // whether a filter should be returned
+	}{
+		"no providers": {
+			providers: nil,
+			want:      false,
+		},
+		"single provider": {
+			providers: []*dag.JWTProvider{{
+				Name:    "google",
+				Issuer:  "https://accounts.google.com",
+				JWKSURI: "https://www.googleapis.com/oauth2/v3/certs",
+			}},
+			want: true,
+		},
+		"multiple providers": {
+			providers: []*dag.JWTProvider{{
+				Name:    "google",
+				JWKSURI: "https://www.googleapis.com/oauth2/v3/certs",
+			}, {
+				Name:    "auth0",
+				JWKSURI: "https://example.auth0.com/.well-known/jwks.json",
+			}},
+			want: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := JWTAuthnFilter(tc.providers)
+			if tc.want && got == nil {
+				t.Fatal("expected a filter, got nil")
+			}
+			if !tc.want && got != nil {
+				t.Fatal("expected no filter, got one")
+			}
+			if got == nil {
+				return
+			}
+			if got.Name != JWTAuthnFilterName {
+				t.Fatalf("got filter name %q, want %q", got.Name, JWTAuthnFilterName)
+			}
+		})
+	}
+}
+
+func TestJWTProvidersForVirtualHosts(t *testing.T) {
+	google := &dag.JWTProvider{Name: "google", JWKSURI: "https://www.googleapis.com/oauth2/v3/certs"}
+	auth0 := &dag.JWTProvider{Name: "auth0", JWKSURI: "https://example.auth0.com/.well-known/jwks.json"}
+
+	tests := map[string]struct {
+		vhosts []*dag.VirtualHost
+		want   []*dag.JWTProvider
+	}{
+		"no virtualhosts": {
+			vhosts: nil,
+			want:   nil,
+		},
+		"single virtualhost, single provider": {
+			vhosts: []*dag.VirtualHost{{JWTProviders: []*dag.JWTProvider{google}}},
+			want:   []*dag.JWTProvider{google},
+		},
+		"single virtualhost, multiple providers": {
+			vhosts: []*dag.VirtualHost{{JWTProviders: []*dag.JWTProvider{google, auth0}}},
+			want:   []*dag.JWTProvider{google, auth0},
+		},
+		"same provider reused across virtualhosts is deduplicated": {
+			vhosts: []*dag.VirtualHost{
+				{JWTProviders: []*dag.JWTProvider{google}},
+				{JWTProviders: []*dag.JWTProvider{google}},
+			},
+			want: []*dag.JWTProvider{google},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := JWTProvidersForVirtualHosts(tc.vhosts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestJWTProviderAudiences(t *testing.T) {
+	p := &dag.JWTProvider{
+		Name:      "google",
+		Issuer:    "https://accounts.google.com",
+		JWKSURI:   "https://www.googleapis.com/oauth2/v3/certs",
+		Audiences: []string{"client1", "client2"},
+	}
+
+	got := jwtProvider(p)
+
+	want := []string{"client1", "client2"}
+	if diff := cmp.Diff(want, got.Audiences); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestJWTPerRoute(t *testing.T) {
+	tests := map[string]struct {
+		requires *dag.JWTRequires
+		want     *jwt_authn.JwtRequirement
+	}{
+		"single provider": {
+			requires: &dag.JWTRequires{
+				ProviderName: "google",
+			},
+			want: &jwt_authn.JwtRequirement{
+				RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+					ProviderName: "google",
+				},
+			},
+		},
+		"allow missing": {
+			requires: &dag.JWTRequires{
+				ProviderName: "google",
+				AllowMissing: true,
+			},
+			want: &jwt_authn.JwtRequirement{
+				RequiresType: &jwt_authn.JwtRequirement_RequiresAny{
+					RequiresAny: &jwt_authn.JwtRequirementOrList{
+						Requirements: []*jwt_authn.JwtRequirement{{
+							RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+								ProviderName: "google",
+							},
+						}, {
+							RequiresType: &jwt_authn.JwtRequirement_AllowMissing{
+								AllowMissing: &types.Empty{},
+							},
+						}},
+					},
+				},
+			},
+		},
+		"requires any": {
+			requires: &dag.JWTRequires{
+				RequiresAny: []string{"google", "auth0"},
+			},
+			want: &jwt_authn.JwtRequirement{
+				RequiresType: &jwt_authn.JwtRequirement_RequiresAny{
+					RequiresAny: &jwt_authn.JwtRequirementOrList{
+						Requirements: []*jwt_authn.JwtRequirement{{
+							RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+								ProviderName: "google",
+							},
+						}, {
+							RequiresType: &jwt_authn.JwtRequirement_ProviderName{
+								ProviderName: "auth0",
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := jwtRequirement(tc.requires)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
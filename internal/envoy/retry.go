@@ -0,0 +1,76 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	"github.com/heptio/contour/internal/dag"
+)
+
+// retryPolicy translates a dag.RetryPolicy into the route.RetryPolicy
+// Envoy expects, including the optional retriable status codes,
+// backoff, and header match conditions.
+func retryPolicy(rp *dag.RetryPolicy) *route.RetryPolicy {
+	numRetries := rp.NumRetries
+	if numRetries == 0 {
+		// NumRetries defaults to 1 when not supplied.
+		numRetries = 1
+	}
+	return &route.RetryPolicy{
+		RetryOn:                 rp.RetryOn,
+		NumRetries:              u32(numRetries),
+		PerTryTimeout:           duration(rp.PerTryTimeout),
+		RetriableStatusCodes:    rp.RetriableStatusCodes,
+		RetryBackOff:            retryBackOff(rp.RetryBackOff),
+		RetriableRequestHeaders: headerMatchers(rp.RetriableRequestHeaders),
+		RetriableHeaders:        headerMatchers(rp.RetriableHeaders),
+	}
+}
+
+func retryBackOff(bo *dag.RetryBackOff) *route.RetryPolicy_RetryBackOff {
+	if bo == nil {
+		return nil
+	}
+	var rb route.RetryPolicy_RetryBackOff
+	if bo.BaseInterval != 0 {
+		rb.BaseInterval = duration(bo.BaseInterval)
+	}
+	if bo.MaxInterval != 0 {
+		rb.MaxInterval = duration(bo.MaxInterval)
+	}
+	return &rb
+}
+
+func headerMatchers(conditions []dag.HeaderMatchCondition) []*route.HeaderMatcher {
+	if len(conditions) == 0 {
+		return nil
+	}
+	matchers := make([]*route.HeaderMatcher, 0, len(conditions))
+	for _, c := range conditions {
+		hm := &route.HeaderMatcher{
+			Name: c.Name,
+		}
+		if c.Present {
+			hm.HeaderMatchSpecifier = &route.HeaderMatcher_PresentMatch{
+				PresentMatch: true,
+			}
+		} else {
+			hm.HeaderMatchSpecifier = &route.HeaderMatcher_ExactMatch{
+				ExactMatch: c.Value,
+			}
+		}
+		matchers = append(matchers, hm)
+	}
+	return matchers
+}
@@ -0,0 +1,151 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	"github.com/google/go-cmp/cmp"
+	"github.com/heptio/contour/internal/dag"
+)
+
+func TestRetryBackOff(t *testing.T) {
+	tests := map[string]struct {
+		backoff *dag.RetryBackOff
+		want    *route.RetryPolicy_RetryBackOff
+	}{
+		"nil": {
+			backoff: nil,
+			want:    nil,
+		},
+		"base only": {
+			backoff: &dag.RetryBackOff{
+				BaseInterval: 25 * time.Millisecond,
+			},
+			want: &route.RetryPolicy_RetryBackOff{
+				BaseInterval: duration(25 * time.Millisecond),
+			},
+		},
+		"base and max": {
+			backoff: &dag.RetryBackOff{
+				BaseInterval: 25 * time.Millisecond,
+				MaxInterval:  250 * time.Millisecond,
+			},
+			want: &route.RetryPolicy_RetryBackOff{
+				BaseInterval: duration(25 * time.Millisecond),
+				MaxInterval:  duration(250 * time.Millisecond),
+			},
+		},
+		"max only": {
+			backoff: &dag.RetryBackOff{
+				MaxInterval: 250 * time.Millisecond,
+			},
+			want: &route.RetryPolicy_RetryBackOff{
+				MaxInterval: duration(250 * time.Millisecond),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := retryBackOff(tc.backoff)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRequestVsResponseHeaderMatchers(t *testing.T) {
+	rp := &dag.RetryPolicy{
+		RetryOn: "5xx",
+		RetriableRequestHeaders: []dag.HeaderMatchCondition{{
+			Name:  "x-canary",
+			Value: "true",
+		}},
+		RetriableHeaders: []dag.HeaderMatchCondition{{
+			Name:  "x-retry-me",
+			Value: "yes",
+		}},
+	}
+
+	got := retryPolicy(rp)
+
+	wantRequest := []*route.HeaderMatcher{{
+		Name: "x-canary",
+		HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+			ExactMatch: "true",
+		},
+	}}
+	if diff := cmp.Diff(wantRequest, got.RetriableRequestHeaders); diff != "" {
+		t.Fatalf("RetriableRequestHeaders: %s", diff)
+	}
+
+	wantResponse := []*route.HeaderMatcher{{
+		Name: "x-retry-me",
+		HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+			ExactMatch: "yes",
+		},
+	}}
+	if diff := cmp.Diff(wantResponse, got.RetriableHeaders); diff != "" {
+		t.Fatalf("RetriableHeaders: %s", diff)
+	}
+}
+
+func TestHeaderMatchers(t *testing.T) {
+	tests := map[string]struct {
+		conditions []dag.HeaderMatchCondition
+		want       []*route.HeaderMatcher
+	}{
+		"none": {
+			conditions: nil,
+			want:       nil,
+		},
+		"exact match": {
+			conditions: []dag.HeaderMatchCondition{{
+				Name:  "x-request-id",
+				Value: "canary",
+			}},
+			want: []*route.HeaderMatcher{{
+				Name: "x-request-id",
+				HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+					ExactMatch: "canary",
+				},
+			}},
+		},
+		"present match": {
+			conditions: []dag.HeaderMatchCondition{{
+				Name:    "x-retry-eligible",
+				Present: true,
+			}},
+			want: []*route.HeaderMatcher{{
+				Name: "x-retry-eligible",
+				HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{
+					PresentMatch: true,
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := headerMatchers(tc.conditions)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
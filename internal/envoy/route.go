@@ -0,0 +1,188 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoy
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	"github.com/gogo/protobuf/types"
+	"github.com/heptio/contour/internal/dag"
+)
+
+// RouteRoute creates a route.Route_Route for the supplied dag.Route and
+// its candidate clusters.
+func RouteRoute(r *dag.Route, clusters []*dag.Cluster) *route.Route_Route {
+	var ra route.RouteAction
+	switch len(clusters) {
+	case 1:
+		ra.ClusterSpecifier = &route.RouteAction_Cluster{
+			Cluster: Clustername(clusters[0]),
+		}
+	default:
+		ra.ClusterSpecifier = &route.RouteAction_WeightedClusters{
+			WeightedClusters: weightedClusters(clusters),
+		}
+	}
+	if r.Websocket {
+		ra.UpgradeConfigs = append(ra.UpgradeConfigs, &route.RouteAction_UpgradeConfig{
+			UpgradeType: "websocket",
+		})
+	}
+	if rp := r.RetryPolicy; rp != nil && rp.RetryOn != "" {
+		ra.RetryPolicy = retryPolicy(rp)
+	}
+	if tp := r.TimeoutPolicy; tp != nil {
+		if tp.Timeout != 0 {
+			ra.Timeout = duration(timeout(tp.Timeout))
+		}
+		if tp.IdleTimeout != 0 {
+			ra.IdleTimeout = duration(timeout(tp.IdleTimeout))
+		}
+	}
+	ra.RequestHeadersToAdd = headersToAdd(r.Headers.RequestHeadersToAdd)
+	ra.RequestHeadersToRemove = r.Headers.RequestHeadersToRemove
+	ra.ResponseHeadersToAdd = headersToAdd(r.Headers.ResponseHeadersToAdd)
+	ra.ResponseHeadersToRemove = r.Headers.ResponseHeadersToRemove
+	return &route.Route_Route{
+		Route: &ra,
+	}
+}
+
+// Route builds the full route.Route for the supplied dag.Route and its
+// candidate clusters, matching on r.Prefix and attaching r's JWT
+// requirement, if any, as the jwt_authn filter's per-route override so
+// it actually reaches Envoy alongside the RouteAction RouteRoute builds.
+func Route(r *dag.Route, clusters []*dag.Cluster) *route.Route {
+	rt := &route.Route{
+		Match:  PrefixMatch(r.Prefix),
+		Action: RouteRoute(r, clusters),
+	}
+	if pr := JWTPerRoute(r.JWTRequires); pr != nil {
+		rt.TypedPerFilterConfig = map[string]*types.Any{
+			JWTAuthnFilterName: toAny(pr),
+		}
+	}
+	return rt
+}
+
+// timeout translates a TimeoutPolicy duration into the corresponding
+// Envoy value, mapping our -1 "infinite" sentinel to Envoy's own
+// "0 means infinite" convention.
+func timeout(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// weightedClusters returns a route.WeightedCluster for the supplied set
+// of dag.Cluster. If no cluster carries an explicit weight, every
+// cluster is given a weight of 1 so that traffic is shared evenly;
+// otherwise a cluster without a weight keeps a weight of 0. The entries
+// are sorted by cluster name, then by weight.
+func weightedClusters(clusters []*dag.Cluster) *route.WeightedCluster {
+	var wc route.WeightedCluster
+	var total uint32
+	for _, c := range clusters {
+		total += c.Weight
+	}
+	// If none of the clusters carry an explicit weight, split the
+	// traffic evenly between them instead of sending it all nowhere.
+	equal := total == 0
+	total = 0
+	for _, c := range clusters {
+		weight := c.Weight
+		if equal {
+			weight = 1
+		}
+		wc.Clusters = append(wc.Clusters, &route.WeightedCluster_ClusterWeight{
+			Name:                    Clustername(c),
+			Weight:                  u32(weight),
+			RequestHeadersToAdd:     headersToAdd(c.Headers.RequestHeadersToAdd),
+			RequestHeadersToRemove:  c.Headers.RequestHeadersToRemove,
+			ResponseHeadersToAdd:    headersToAdd(c.Headers.ResponseHeadersToAdd),
+			ResponseHeadersToRemove: c.Headers.ResponseHeadersToRemove,
+		})
+		total += weight
+	}
+	wc.TotalWeight = u32(total)
+	sort.SliceStable(wc.Clusters, func(i, j int) bool {
+		a, b := wc.Clusters[i], wc.Clusters[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Weight.Value < b.Weight.Value
+	})
+	return &wc
+}
+
+// VirtualHost creates a route.VirtualHost for the supplied hostname and
+// port. Port zero indicates that the virtual host should match requests
+// on any port, so only the plain hostname is registered as a domain.
+// headers, if non-zero, is applied to every Route on the VirtualHost.
+func VirtualHost(hostname string, port int, headers dag.Headers) route.VirtualHost {
+	domains := []string{hostname}
+	if port != 0 && hostname != "*" {
+		domains = append(domains, fmt.Sprintf("%s:%d", hostname, port))
+	}
+	return route.VirtualHost{
+		Name:                    hostname,
+		Domains:                 domains,
+		RequestHeadersToAdd:     headersToAdd(headers.RequestHeadersToAdd),
+		RequestHeadersToRemove:  headers.RequestHeadersToRemove,
+		ResponseHeadersToAdd:    headersToAdd(headers.ResponseHeadersToAdd),
+		ResponseHeadersToRemove: headers.ResponseHeadersToRemove,
+	}
+}
+
+// PrefixMatch creates a route.RouteMatch for the supplied prefix.
+func PrefixMatch(prefix string) route.RouteMatch {
+	return route.RouteMatch{
+		PathSpecifier: &route.RouteMatch_Prefix{
+			Prefix: prefix,
+		},
+	}
+}
+
+// UpgradeHTTPS creates a route.Route_Redirect that redirects the
+// request to HTTPS.
+func UpgradeHTTPS() *route.Route_Redirect {
+	return &route.Route_Redirect{
+		Redirect: &route.RedirectAction{
+			SchemeRewriteSpecifier: &route.RedirectAction_HttpsRedirect{
+				HttpsRedirect: true,
+			},
+		},
+	}
+}
+
+// Clustername returns the name of the Envoy cluster that will be
+// created for the supplied dag.Cluster. The last path segment is a
+// hash of the Cluster's ConnectTimeout, the only Cluster setting
+// beyond the service name/port that reaches the Envoy cluster itself.
+func Clustername(c *dag.Cluster) string {
+	u := c.Upstream
+	hash := sha1.New()
+	// Weight is carried in the WeightedCluster entry itself, so it is
+	// deliberately excluded here -- two Clusters that differ only by
+	// weight must produce the same Envoy cluster name. ConnectTimeout
+	// is fed in so that two Clusters for the same service/port with
+	// different connection timeouts do not collide.
+	fmt.Fprintf(hash, "%d", c.ConnectTimeout)
+	return fmt.Sprintf("%s/%s/%d/%x", u.Namespace, u.Name, u.ServicePort.Port, hash.Sum(nil)[:5])
+}
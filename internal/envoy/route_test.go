@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	"github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
 	"github.com/google/go-cmp/cmp"
 	"github.com/heptio/contour/internal/dag"
@@ -61,7 +62,7 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 				},
 			},
@@ -76,7 +77,7 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 					UpgradeConfigs: []*route.RouteAction_UpgradeConfig{{
 						UpgradeType: "websocket",
@@ -107,10 +108,10 @@ func TestRouteRoute(t *testing.T) {
 					ClusterSpecifier: &route.RouteAction_WeightedClusters{
 						WeightedClusters: &route.WeightedCluster{
 							Clusters: []*route.WeightedCluster_ClusterWeight{{
-								Name:   "default/kuard/8080/da39a3ee5e",
+								Name:   "default/kuard/8080/b6589fc6ab",
 								Weight: u32(0),
 							}, {
-								Name:   "default/kuard/8080/da39a3ee5e",
+								Name:   "default/kuard/8080/b6589fc6ab",
 								Weight: u32(90),
 							}},
 							TotalWeight: u32(90),
@@ -143,10 +144,10 @@ func TestRouteRoute(t *testing.T) {
 					ClusterSpecifier: &route.RouteAction_WeightedClusters{
 						WeightedClusters: &route.WeightedCluster{
 							Clusters: []*route.WeightedCluster_ClusterWeight{{
-								Name:   "default/kuard/8080/da39a3ee5e",
+								Name:   "default/kuard/8080/b6589fc6ab",
 								Weight: u32(0),
 							}, {
-								Name:   "default/kuard/8080/da39a3ee5e",
+								Name:   "default/kuard/8080/b6589fc6ab",
 								Weight: u32(90),
 							}},
 							TotalWeight: u32(90),
@@ -169,7 +170,7 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 				},
 			},
@@ -187,7 +188,7 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 					RetryPolicy: &route.RetryPolicy{
 						RetryOn:       "503",
@@ -197,6 +198,93 @@ func TestRouteRoute(t *testing.T) {
 				},
 			},
 		},
+		"retry-on: retriable-status-codes": {
+			route: &dag.Route{
+				Prefix: "/",
+				RetryPolicy: &dag.RetryPolicy{
+					RetryOn:              "retriable-status-codes",
+					NumRetries:           3,
+					PerTryTimeout:        1 * time.Second,
+					RetriableStatusCodes: []uint32{502, 503, 504},
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					RetryPolicy: &route.RetryPolicy{
+						RetryOn:              "retriable-status-codes",
+						NumRetries:           u32(3),
+						PerTryTimeout:        duration(1 * time.Second),
+						RetriableStatusCodes: []uint32{502, 503, 504},
+					},
+				},
+			},
+		},
+		"retry backoff 25ms/250ms": {
+			route: &dag.Route{
+				Prefix: "/",
+				RetryPolicy: &dag.RetryPolicy{
+					RetryOn:    "5xx",
+					NumRetries: 4,
+					RetryBackOff: &dag.RetryBackOff{
+						BaseInterval: 25 * time.Millisecond,
+						MaxInterval:  250 * time.Millisecond,
+					},
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					RetryPolicy: &route.RetryPolicy{
+						RetryOn:       "5xx",
+						NumRetries:    u32(4),
+						PerTryTimeout: duration(0),
+						RetryBackOff: &route.RetryPolicy_RetryBackOff{
+							BaseInterval: duration(25 * time.Millisecond),
+							MaxInterval:  duration(250 * time.Millisecond),
+						},
+					},
+				},
+			},
+		},
+		"retry-on: 5xx with header match": {
+			route: &dag.Route{
+				Prefix: "/",
+				RetryPolicy: &dag.RetryPolicy{
+					RetryOn:    "5xx",
+					NumRetries: 2,
+					RetriableRequestHeaders: []dag.HeaderMatchCondition{{
+						Name:  "x-canary",
+						Value: "true",
+					}},
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					RetryPolicy: &route.RetryPolicy{
+						RetryOn:       "5xx",
+						NumRetries:    u32(2),
+						PerTryTimeout: duration(0),
+						RetriableRequestHeaders: []*route.HeaderMatcher{{
+							Name: "x-canary",
+							HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{
+								ExactMatch: "true",
+							},
+						}},
+					},
+				},
+			},
+		},
 		"timeout 90s": {
 			route: &dag.Route{
 				Prefix: "/",
@@ -208,7 +296,7 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 					Timeout: duration(90 * time.Second),
 				},
@@ -225,12 +313,97 @@ func TestRouteRoute(t *testing.T) {
 			want: &route.Route_Route{
 				Route: &route.RouteAction{
 					ClusterSpecifier: &route.RouteAction_Cluster{
-						Cluster: "default/kuard/8080/da39a3ee5e",
+						Cluster: "default/kuard/8080/b6589fc6ab",
 					},
 					Timeout: duration(0),
 				},
 			},
 		},
+		"request 30s + idle 5m": {
+			route: &dag.Route{
+				Prefix: "/",
+				TimeoutPolicy: &dag.TimeoutPolicy{
+					Timeout:     30 * time.Second,
+					IdleTimeout: 5 * time.Minute,
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					Timeout:     duration(30 * time.Second),
+					IdleTimeout: duration(5 * time.Minute),
+				},
+			},
+		},
+		"request infinite + idle 60s": {
+			route: &dag.Route{
+				Prefix: "/",
+				TimeoutPolicy: &dag.TimeoutPolicy{
+					Timeout:     -1,
+					IdleTimeout: 60 * time.Second,
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					Timeout:     duration(0),
+					IdleTimeout: duration(60 * time.Second),
+				},
+			},
+		},
+		"idle 60s, request unset": {
+			route: &dag.Route{
+				Prefix: "/",
+				TimeoutPolicy: &dag.TimeoutPolicy{
+					IdleTimeout: 60 * time.Second,
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					IdleTimeout: duration(60 * time.Second),
+				},
+			},
+		},
+		"per-route header manipulation": {
+			route: &dag.Route{
+				Prefix: "/",
+				Headers: dag.Headers{
+					RequestHeadersToAdd: []dag.HeaderValue{{
+						Name:  "x-request-start",
+						Value: "%START_TIME(%s.%3f)%",
+					}},
+					RequestHeadersToRemove:  []string{"x-forwarded-client"},
+					ResponseHeadersToRemove: []string{"x-internal-debug"},
+				},
+			},
+			clusters: []*dag.Cluster{c1},
+			want: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: "default/kuard/8080/b6589fc6ab",
+					},
+					RequestHeadersToAdd: []*envoy_api_v2_core.HeaderValueOption{{
+						Header: &envoy_api_v2_core.HeaderValue{
+							Key:   "x-request-start",
+							Value: "%START_TIME(%s.%3f)%",
+						},
+						Append: bool_(false),
+					}},
+					RequestHeadersToRemove:  []string{"x-forwarded-client"},
+					ResponseHeadersToRemove: []string{"x-internal-debug"},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -243,6 +416,72 @@ func TestRouteRoute(t *testing.T) {
 	}
 }
 
+func TestRouteJWTPerRouteAttachment(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+	c1 := &dag.Cluster{
+		Upstream: &dag.TCPService{
+			Name:        s1.Name,
+			Namespace:   s1.Namespace,
+			ServicePort: &s1.Spec.Ports[0],
+		},
+	}
+
+	tests := map[string]struct {
+		requires *dag.JWTRequires
+		want     bool // whether TypedPerFilterConfig should be set
+	}{
+		"no requirement": {
+			requires: nil,
+			want:     false,
+		},
+		"single provider": {
+			requires: &dag.JWTRequires{ProviderName: "google"},
+			want:     true,
+		},
+		"requires any (multi-provider)": {
+			requires: &dag.JWTRequires{RequiresAny: []string{"google", "auth0"}},
+			want:     true,
+		},
+		"allow missing": {
+			requires: &dag.JWTRequires{ProviderName: "google", AllowMissing: true},
+			want:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &dag.Route{Prefix: "/", JWTRequires: tc.requires}
+			got := Route(r, []*dag.Cluster{c1})
+
+			if got.Match.GetPrefix() != "/" {
+				t.Fatalf("expected the route's Match to be wired through, got %v", got.Match)
+			}
+			if got.Action == nil {
+				t.Fatal("expected the route's Action to be wired through, got nil")
+			}
+
+			_, hasConfig := got.TypedPerFilterConfig[JWTAuthnFilterName]
+			if tc.want && !hasConfig {
+				t.Fatal("expected a jwt_authn TypedPerFilterConfig entry, got none")
+			}
+			if !tc.want && hasConfig {
+				t.Fatal("expected no jwt_authn TypedPerFilterConfig entry, got one")
+			}
+		})
+	}
+}
+
 func TestWeightedClusters(t *testing.T) {
 	tests := map[string]struct {
 		clusters []*dag.Cluster
@@ -268,10 +507,10 @@ func TestWeightedClusters(t *testing.T) {
 			}},
 			want: &route.WeightedCluster{
 				Clusters: []*route.WeightedCluster_ClusterWeight{{
-					Name:   "default/kuard/8080/da39a3ee5e",
+					Name:   "default/kuard/8080/b6589fc6ab",
 					Weight: u32(1),
 				}, {
-					Name:   "default/nginx/8080/da39a3ee5e",
+					Name:   "default/nginx/8080/b6589fc6ab",
 					Weight: u32(1),
 				}},
 				TotalWeight: u32(2),
@@ -299,10 +538,10 @@ func TestWeightedClusters(t *testing.T) {
 			}},
 			want: &route.WeightedCluster{
 				Clusters: []*route.WeightedCluster_ClusterWeight{{
-					Name:   "default/kuard/8080/da39a3ee5e",
+					Name:   "default/kuard/8080/b6589fc6ab",
 					Weight: u32(80),
 				}, {
-					Name:   "default/nginx/8080/da39a3ee5e",
+					Name:   "default/nginx/8080/b6589fc6ab",
 					Weight: u32(20),
 				}},
 				TotalWeight: u32(100),
@@ -338,18 +577,62 @@ func TestWeightedClusters(t *testing.T) {
 			}},
 			want: &route.WeightedCluster{
 				Clusters: []*route.WeightedCluster_ClusterWeight{{
-					Name:   "default/kuard/8080/da39a3ee5e",
+					Name:   "default/kuard/8080/b6589fc6ab",
 					Weight: u32(80),
 				}, {
-					Name:   "default/nginx/8080/da39a3ee5e",
+					Name:   "default/nginx/8080/b6589fc6ab",
 					Weight: u32(20),
 				}, {
-					Name:   "default/notraffic/8080/da39a3ee5e",
+					Name:   "default/notraffic/8080/b6589fc6ab",
 					Weight: u32(0),
 				}},
 				TotalWeight: u32(100),
 			},
 		},
+		"per-weighted-cluster header manipulation": {
+			clusters: []*dag.Cluster{{
+				Upstream: &dag.TCPService{
+					Name:      "kuard",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Port: 8080,
+					},
+				},
+				Weight: 80,
+				Headers: dag.Headers{
+					ResponseHeadersToAdd: []dag.HeaderValue{{
+						Name:  "x-shadow",
+						Value: "kuard",
+					}},
+				},
+			}, {
+				Upstream: &dag.TCPService{
+					Name:      "nginx",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Port: 8080,
+					},
+				},
+				Weight: 20,
+			}},
+			want: &route.WeightedCluster{
+				Clusters: []*route.WeightedCluster_ClusterWeight{{
+					Name:   "default/kuard/8080/b6589fc6ab",
+					Weight: u32(80),
+					ResponseHeadersToAdd: []*envoy_api_v2_core.HeaderValueOption{{
+						Header: &envoy_api_v2_core.HeaderValue{
+							Key:   "x-shadow",
+							Value: "kuard",
+						},
+						Append: bool_(false),
+					}},
+				}, {
+					Name:   "default/nginx/8080/b6589fc6ab",
+					Weight: u32(20),
+				}},
+				TotalWeight: u32(100),
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -366,6 +649,7 @@ func TestVirtualHost(t *testing.T) {
 	tests := map[string]struct {
 		hostname string
 		port     int
+		headers  dag.Headers
 		want     route.VirtualHost
 	}{
 		"default hostname": {
@@ -384,10 +668,33 @@ func TestVirtualHost(t *testing.T) {
 				Domains: []string{"www.example.com", "www.example.com:9999"},
 			},
 		},
+		"per-vhost header manipulation": {
+			hostname: "www.example.com",
+			port:     9999,
+			headers: dag.Headers{
+				RequestHeadersToAdd: []dag.HeaderValue{{
+					Name:  "x-request-start",
+					Value: "%START_TIME(%s.%3f)%",
+				}},
+				ResponseHeadersToRemove: []string{"x-internal-debug"},
+			},
+			want: route.VirtualHost{
+				Name:    "www.example.com",
+				Domains: []string{"www.example.com", "www.example.com:9999"},
+				RequestHeadersToAdd: []*envoy_api_v2_core.HeaderValueOption{{
+					Header: &envoy_api_v2_core.HeaderValue{
+						Key:   "x-request-start",
+						Value: "%START_TIME(%s.%3f)%",
+					},
+					Append: bool_(false),
+				}},
+				ResponseHeadersToRemove: []string{"x-internal-debug"},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := VirtualHost(tc.hostname, tc.port)
+			got := VirtualHost(tc.hostname, tc.port, tc.headers)
 			if diff := cmp.Diff(got, tc.want); diff != "" {
 				t.Fatal(diff)
 			}
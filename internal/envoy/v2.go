@@ -0,0 +1,57 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envoy translates the DAG into Envoy v2 API objects.
+package envoy
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+)
+
+// u32 returns a *types.UInt32Value for v.
+func u32(v uint32) *types.UInt32Value {
+	return &types.UInt32Value{
+		Value: v,
+	}
+}
+
+// bool_ returns a *types.BoolValue for v.
+func bool_(v bool) *types.BoolValue {
+	return &types.BoolValue{
+		Value: v,
+	}
+}
+
+// duration converts a time.Duration into the protobuf duration used
+// throughout the Envoy v2 API.
+func duration(d time.Duration) *types.Duration {
+	return &types.Duration{
+		Seconds: int64(d / time.Second),
+		Nanos:   int32(d % time.Second),
+	}
+}
+
+// toAny marshals m into a types.Any, panicking if m cannot be
+// marshaled. The filter and cluster config types passed to this helper
+// are all statically known at compile time, so a marshaling failure
+// indicates a programming error rather than bad input.
+func toAny(m proto.Message) *types.Any {
+	a, err := types.MarshalAny(m)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
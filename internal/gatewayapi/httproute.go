@@ -0,0 +1,125 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/heptio/contour/internal/dag"
+)
+
+// Routes is implemented by the cache the caller maintains of the
+// cluster's Gateway API objects. Provider only needs to look up routes
+// attached to a specific Gateway listener; it does not watch or list
+// objects itself.
+type Routes interface {
+	HTTPRoutesFor(gateway *gatewayapi_v1beta1.Gateway, listener *gatewayapi_v1beta1.Listener) []*gatewayapi_v1beta1.HTTPRoute
+}
+
+// buildHTTPRoute translates a single HTTPRoute into the dag.VirtualHost
+// it contributes to hostname, reusing the same dag.Route/dag.Cluster
+// shapes IngressRoute produces so that the envoy package's translators
+// (RouteRoute, weightedClusters, PrefixMatch, ...) need no changes to
+// serve either provider.
+func buildHTTPRoute(hostname string, hr *gatewayapi_v1beta1.HTTPRoute) (*dag.VirtualHost, RouteStatus) {
+	vhost := &dag.VirtualHost{
+		Name: hostname,
+	}
+	status := RouteStatus{
+		Name:      hr.Name,
+		Namespace: hr.Namespace,
+	}
+
+	resolved := true
+	var resolvedErr string
+	for _, rule := range hr.Spec.Rules {
+		clusters, err := backendsToClusters(hr.Namespace, rule.BackendRefs)
+		if err != nil {
+			resolved = false
+			resolvedErr = err.Error()
+			continue
+		}
+		if len(rule.Matches) == 0 {
+			vhost.Routes = append(vhost.Routes, &dag.Route{
+				Prefix:   "/",
+				Clusters: clusters,
+			})
+			continue
+		}
+		for _, match := range rule.Matches {
+			vhost.Routes = append(vhost.Routes, &dag.Route{
+				Prefix:   pathPrefix(match),
+				Clusters: clusters,
+			})
+		}
+	}
+
+	status.Conditions = append(status.Conditions, conditionResolvedRefs(resolved, resolvedErr))
+	if len(vhost.Routes) == 0 {
+		return nil, status
+	}
+	return vhost, status
+}
+
+// pathPrefix extracts the Prefix dag.Route understands from an
+// HTTPRouteMatch. Only the path's Value is read, and it is always
+// matched as a prefix: an exact or regular-expression path match is
+// treated as a prefix match of its Value. Header and method matches
+// are ignored.
+func pathPrefix(match gatewayapi_v1beta1.HTTPRouteMatch) string {
+	if match.Path == nil || match.Path.Value == nil {
+		return "/"
+	}
+	return *match.Path.Value
+}
+
+// backendsToClusters converts an HTTPRoute rule's backendRefs into
+// dag.Cluster entries, carrying each backendRef's weight straight
+// through as the dag.Cluster's Weight so the existing weightedClusters
+// translator needs no changes to split traffic across them. A
+// backendRef naming a different namespace requires a ReferenceGrant
+// permitting it; that check belongs to the cache Routes is backed by,
+// since only it knows the full set of ReferenceGrants in the cluster.
+func backendsToClusters(namespace string, refs []gatewayapi_v1beta1.HTTPBackendRef) ([]*dag.Cluster, error) {
+	clusters := make([]*dag.Cluster, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name == "" {
+			return nil, fmt.Errorf("backendRef name is required")
+		}
+		ns := namespace
+		if ref.Namespace != nil && *ref.Namespace != "" {
+			ns = string(*ref.Namespace)
+		}
+		port := v1.ServicePort{}
+		if ref.Port != nil {
+			port.Port = int32(*ref.Port)
+		}
+		var weight uint32
+		if ref.Weight != nil {
+			weight = uint32(*ref.Weight)
+		}
+		clusters = append(clusters, &dag.Cluster{
+			Upstream: &dag.TCPService{
+				Name:        string(ref.Name),
+				Namespace:   ns,
+				ServicePort: &port,
+			},
+			Weight: weight,
+		})
+	}
+	return clusters, nil
+}
@@ -0,0 +1,133 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func countConditions(conditions []metav1.Condition, condType string) int {
+	count := 0
+	for _, c := range conditions {
+		if c.Type == condType {
+			count++
+		}
+	}
+	return count
+}
+
+func backendRef(name string, weight int32) gatewayapi_v1beta1.HTTPBackendRef {
+	w := weight
+	return gatewayapi_v1beta1.HTTPBackendRef{
+		BackendRef: gatewayapi_v1beta1.BackendRef{
+			BackendObjectReference: gatewayapi_v1beta1.BackendObjectReference{
+				Name: gatewayapi_v1beta1.ObjectName(name),
+			},
+			Weight: &w,
+		},
+	}
+}
+
+func TestBuildHTTPRouteSingleRule(t *testing.T) {
+	hr := &gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{backendRef("kuard", 1)},
+			}},
+		},
+	}
+
+	vhost, status := buildHTTPRoute("www.example.com", hr)
+	if vhost == nil {
+		t.Fatal("expected a virtualhost, got nil")
+	}
+	if len(vhost.Routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(vhost.Routes))
+	}
+	if got := vhost.Routes[0].Prefix; got != "/" {
+		t.Fatalf("expected default prefix \"/\", got %q", got)
+	}
+	if len(vhost.Routes[0].Clusters) != 1 {
+		t.Fatalf("expected 1 cluster, got %d", len(vhost.Routes[0].Clusters))
+	}
+	if got := vhost.Routes[0].Clusters[0].Upstream.Name; got != "kuard" {
+		t.Fatalf("expected upstream %q, got %q", "kuard", got)
+	}
+	if len(status.Conditions) == 0 {
+		t.Fatal("expected at least one condition to be recorded")
+	}
+}
+
+func TestBuildHTTPRouteWeightedSplit(t *testing.T) {
+	hr := &gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "split",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{{
+				BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{
+					backendRef("v1", 90),
+					backendRef("v2", 10),
+				},
+			}},
+		},
+	}
+
+	vhost, _ := buildHTTPRoute("www.example.com", hr)
+	clusters := vhost.Routes[0].Clusters
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].Weight != 90 || clusters[1].Weight != 10 {
+		t.Fatalf("expected weights 90/10, got %d/%d", clusters[0].Weight, clusters[1].Weight)
+	}
+}
+
+func TestBuildHTTPRouteOneBadRuleYieldsSingleFalseResolvedRefs(t *testing.T) {
+	hr := &gatewayapi_v1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mixed",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.HTTPRouteSpec{
+			Rules: []gatewayapi_v1beta1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{backendRef("", 1)},
+				},
+				{
+					BackendRefs: []gatewayapi_v1beta1.HTTPBackendRef{backendRef("kuard", 1)},
+				},
+			},
+		},
+	}
+
+	_, status := buildHTTPRoute("www.example.com", hr)
+
+	if got := countConditions(status.Conditions, ConditionResolvedRefs); got != 1 {
+		t.Fatalf("expected exactly 1 ResolvedRefs condition, got %d", got)
+	}
+	for _, c := range status.Conditions {
+		if c.Type == ConditionResolvedRefs && c.Status != metav1.ConditionFalse {
+			t.Fatalf("expected ResolvedRefs condition to be False because one rule failed to resolve, got %v", c.Status)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+// FlagName is the contour serve flag operators set to run this
+// provider instead of the default IngressRoute one, e.g.
+// `contour serve --ingress-class-name= --use-gateway-api=true`. It
+// lives here, rather than in the flag-parsing package, so that the
+// name contour serve registers the flag under and the provider it
+// selects cannot drift apart.
+const FlagName = "use-gateway-api"
+
+// DefaultEnabled is the FlagName default: off, so that existing
+// IngressRoute users migrate to this provider explicitly rather than
+// having their DAG silently start coming from a different source.
+const DefaultEnabled = false
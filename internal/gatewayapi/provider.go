@@ -0,0 +1,111 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gatewayapi builds the internal/dag graph from the upstream
+// Kubernetes Gateway API as an alternative to IngressRoute. Everything
+// downstream of the DAG -- the envoy package's translators and the
+// gRPC snapshot cache -- is unaware of which provider produced it.
+//
+// This is an MVP slice of the Gateway API surface: only Gateway and
+// HTTPRoute objects are read (a GatewayClass is matched by name only,
+// see Provider.GatewayClassName), and HTTPRoute matching only
+// understands prefix path matches (see pathPrefix). TLSRoute, TCPRoute,
+// and HTTPRoute header/method matching are not implemented yet.
+package gatewayapi
+
+import (
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/heptio/contour/internal/dag"
+)
+
+// ControllerName is the value operators must set as a GatewayClass's
+// Spec.Controller for Contour to consider it one of its own. Provider
+// itself is not handed GatewayClass objects -- it only sees Gateways
+// and trusts that whatever populated GatewayClassName already
+// resolved the GatewayClass and checked its Controller against this
+// value.
+const ControllerName = "projectcontour.io/gateway-controller"
+
+// Provider builds a dag.VirtualHost graph from Gateway API resources.
+// Unlike the IngressRoute path, Provider does not own a cache of its
+// own; it is handed the objects it needs to consider for a single
+// build and returns the DAG plus the status updates that resulted.
+type Provider struct {
+	// GatewayClassName restricts this Provider to Gateways that
+	// reference a GatewayClass of this name. It must be set: Provider
+	// has no access to the cluster's GatewayClass objects, so it
+	// cannot discover "the first GatewayClass controlled by
+	// ControllerName" on its own. A Gateway whose Spec.GatewayClassName
+	// does not equal this value, or an empty GatewayClassName, means
+	// Build processes nothing for that Gateway.
+	GatewayClassName string
+}
+
+// Build computes the set of dag.VirtualHost produced by the supplied
+// Gateway and its attached routes, along with the status updates that
+// should be written back to the Gateway API objects. It returns an
+// empty Result, with no listener or route status, for a Gateway that
+// does not reference GatewayClassName.
+func (p *Provider) Build(gateway *gatewayapi_v1beta1.Gateway, routes Routes) (*Result, error) {
+	if p.GatewayClassName == "" || string(gateway.Spec.GatewayClassName) != p.GatewayClassName {
+		return &Result{Gateway: StatusFor(gateway)}, nil
+	}
+
+	result := &Result{
+		Gateway: StatusFor(gateway),
+	}
+	result.Gateway.Conditions = conditionsForGateway()
+
+	for i := range gateway.Spec.Listeners {
+		listener := &gateway.Spec.Listeners[i]
+		vhosts, listenerStatus, routeStatuses := p.buildListener(gateway, listener, routes)
+		result.VirtualHosts = append(result.VirtualHosts, vhosts...)
+		result.Listeners = append(result.Listeners, listenerStatus)
+		result.Routes = append(result.Routes, routeStatuses...)
+	}
+
+	return result, nil
+}
+
+// Result is the output of a single Provider.Build call: the portion of
+// the DAG rooted at this Gateway, plus the status Contour should write
+// back onto the Gateway API objects that produced it.
+type Result struct {
+	VirtualHosts []*dag.VirtualHost
+	Gateway      GatewayStatus
+	Listeners    []ListenerStatus
+	Routes       []RouteStatus
+}
+
+func (p *Provider) buildListener(gateway *gatewayapi_v1beta1.Gateway, listener *gatewayapi_v1beta1.Listener, routes Routes) ([]*dag.VirtualHost, ListenerStatus, []RouteStatus) {
+	status := ListenerStatus{Name: string(listener.Name)}
+
+	hostname := "*"
+	if listener.Hostname != nil && *listener.Hostname != "" {
+		hostname = string(*listener.Hostname)
+	}
+
+	var vhosts []*dag.VirtualHost
+	var routeStatuses []RouteStatus
+	for _, hr := range routes.HTTPRoutesFor(gateway, listener) {
+		vh, routeStatus := buildHTTPRoute(hostname, hr)
+		if vh != nil {
+			vhosts = append(vhosts, vh)
+		}
+		status.AttachedRoutes++
+		routeStatuses = append(routeStatuses, routeStatus)
+	}
+	status.Conditions = conditionsForListener(len(vhosts) > 0)
+	return vhosts, status, routeStatuses
+}
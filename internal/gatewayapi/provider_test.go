@@ -0,0 +1,100 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+type noRoutes struct{}
+
+func (noRoutes) HTTPRoutesFor(*gatewayapi_v1beta1.Gateway, *gatewayapi_v1beta1.Listener) []*gatewayapi_v1beta1.HTTPRoute {
+	return nil
+}
+
+func gatewayWithClass(class string) *gatewayapi_v1beta1.Gateway {
+	return &gatewayapi_v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1beta1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1beta1.ObjectName(class),
+			Listeners: []gatewayapi_v1beta1.Listener{
+				{Name: "http"},
+			},
+		},
+	}
+}
+
+func TestProviderBuildIgnoresGatewaysOfOtherClass(t *testing.T) {
+	p := &Provider{GatewayClassName: "contour"}
+
+	result, err := p.Build(gatewayWithClass("other"), noRoutes{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Listeners) != 0 {
+		t.Fatalf("expected no listener status for a Gateway of a different class, got %d", len(result.Listeners))
+	}
+}
+
+func TestProviderBuildProcessesMatchingClass(t *testing.T) {
+	p := &Provider{GatewayClassName: "contour"}
+
+	result, err := p.Build(gatewayWithClass("contour"), noRoutes{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Listeners) != 1 {
+		t.Fatalf("expected 1 listener status for a Gateway of the configured class, got %d", len(result.Listeners))
+	}
+}
+
+func TestProviderBuildSetsGatewayAcceptedCondition(t *testing.T) {
+	p := &Provider{GatewayClassName: "contour"}
+
+	result, err := p.Build(gatewayWithClass("contour"), noRoutes{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var found bool
+	for _, c := range result.Gateway.Conditions {
+		if c.Type == ConditionAccepted {
+			found = true
+			if c.Status != metav1.ConditionTrue {
+				t.Fatalf("expected Accepted condition to be True, got %v", c.Status)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a Gateway-level Accepted condition, got none")
+	}
+}
+
+func TestProviderBuildEmptyClassNameProcessesNoGateway(t *testing.T) {
+	p := &Provider{}
+
+	result, err := p.Build(gatewayWithClass("anything"), noRoutes{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Listeners) != 0 {
+		t.Fatalf("expected no listener status when GatewayClassName is unset, got %d", len(result.Listeners))
+	}
+}
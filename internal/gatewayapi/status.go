@@ -0,0 +1,98 @@
+// Copyright © 2018 Heptio
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayapi
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapi_v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// Condition types and reasons Contour writes back onto Gateway API
+// objects, as defined by the upstream Gateway API spec.
+const (
+	ConditionAccepted     = "Accepted"
+	ConditionResolvedRefs = "ResolvedRefs"
+	ConditionProgrammed   = "Programmed"
+
+	ReasonAccepted        = "Accepted"
+	ReasonResolvedRefs    = "ResolvedRefs"
+	ReasonInvalidBackend  = "InvalidBackendRef"
+	ReasonProgrammed      = "Programmed"
+	ReasonNoAttachedRoute = "NoAttachedRoute"
+)
+
+// GatewayStatus is the subset of a Gateway's status Provider computes.
+type GatewayStatus struct {
+	Name, Namespace string
+	Conditions      []metav1.Condition
+}
+
+// ListenerStatus is the per-listener status Provider computes as part
+// of a Gateway's overall status.
+type ListenerStatus struct {
+	Name           string
+	AttachedRoutes int32
+	Conditions     []metav1.Condition
+}
+
+// RouteStatus is the status Provider computes for a single attached
+// HTTPRoute.
+type RouteStatus struct {
+	Name, Namespace string
+	Conditions      []metav1.Condition
+}
+
+// StatusFor seeds the GatewayStatus that Provider.Build populates as it
+// walks gateway's listeners.
+func StatusFor(gateway *gatewayapi_v1beta1.Gateway) GatewayStatus {
+	return GatewayStatus{
+		Name:      gateway.Name,
+		Namespace: gateway.Namespace,
+	}
+}
+
+func conditionsForGateway() []metav1.Condition {
+	return []metav1.Condition{
+		newCondition(ConditionAccepted, metav1.ConditionTrue, ReasonAccepted, "Gateway accepted"),
+	}
+}
+
+func conditionsForListener(programmed bool) []metav1.Condition {
+	conditions := []metav1.Condition{
+		newCondition(ConditionAccepted, metav1.ConditionTrue, ReasonAccepted, "Listener accepted"),
+	}
+	if programmed {
+		conditions = append(conditions, newCondition(ConditionProgrammed, metav1.ConditionTrue, ReasonProgrammed, "Listener has at least one attached route with a usable rule"))
+	} else {
+		conditions = append(conditions, newCondition(ConditionProgrammed, metav1.ConditionFalse, ReasonNoAttachedRoute, "Listener has no attached route with a usable rule"))
+	}
+	return conditions
+}
+
+func conditionResolvedRefs(resolved bool, message string) metav1.Condition {
+	if resolved {
+		return newCondition(ConditionResolvedRefs, metav1.ConditionTrue, ReasonResolvedRefs, "All references resolved")
+	}
+	return newCondition(ConditionResolvedRefs, metav1.ConditionFalse, ReasonInvalidBackend, message)
+}
+
+func newCondition(condType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    condType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+